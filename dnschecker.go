@@ -2,6 +2,10 @@ package main
 
 import (
 	"bufio"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -15,6 +19,7 @@ import (
 
 	"github.com/atotto/clipboard" // OSに依存しないクリップボード操作のためのライブラリ
 	"github.com/fatih/color"
+	"github.com/miekg/dns"
 )
 
 // ドメインの入力履歴
@@ -32,6 +37,561 @@ var (
 	fqdnRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}$`)
 )
 
+// DNSSECStatus is the validation state of a single answer, as reported by
+// a DNSSEC-aware Resolver. The zero value (empty string) means DNSSEC
+// validation was not requested for this lookup.
+type DNSSECStatus string
+
+const (
+	StatusSecure   DNSSECStatus = "Secure"
+	StatusInsecure DNSSECStatus = "Insecure"
+	StatusBogus    DNSSECStatus = "Bogus"
+)
+
+// CAARecord is a parsed CAA resource record; the stdlib has no equivalent of
+// net.MX/net.NS/net.SRV for this type so Resolver defines its own.
+type CAARecord struct {
+	Flag  uint8
+	Tag   string
+	Value string
+}
+
+// Resolver abstracts DNS lookups so TestA/TestCNAME/TestMX/TestTXT/TestNS/
+// TestAAAA/TestCAA/TestSRV can run either against the OS resolver (default,
+// stdlib net.Lookup*) or against an explicit nameserver via
+// github.com/miekg/dns, optionally requesting DNSSEC validation.
+type Resolver interface {
+	LookupA(name string) (ips []net.IP, status DNSSECStatus, err error)
+	LookupAAAA(name string) (ips []net.IP, status DNSSECStatus, err error)
+	LookupCNAME(name string) (cname string, status DNSSECStatus, err error)
+	LookupMX(name string) (mxs []*net.MX, status DNSSECStatus, err error)
+	LookupTXT(name string) (txts []string, status DNSSECStatus, err error)
+	LookupNS(name string) (nss []*net.NS, status DNSSECStatus, err error)
+	LookupCAA(name string) (caas []CAARecord, status DNSSECStatus, err error)
+	LookupSRV(name string) (srvs []*net.SRV, status DNSSECStatus, err error)
+}
+
+// systemResolver delegates to the stdlib, exactly preserving the historical
+// behaviour of this tool. It never reports a DNSSEC status.
+type systemResolver struct{}
+
+func (systemResolver) LookupA(name string) ([]net.IP, DNSSECStatus, error) {
+	ips, err := net.LookupIP(name)
+	return ips, "", err
+}
+
+func (systemResolver) LookupCNAME(name string) (string, DNSSECStatus, error) {
+	cname, err := net.LookupCNAME(name)
+	return cname, "", err
+}
+
+func (systemResolver) LookupMX(name string) ([]*net.MX, DNSSECStatus, error) {
+	mxs, err := net.LookupMX(name)
+	return mxs, "", err
+}
+
+func (systemResolver) LookupTXT(name string) ([]string, DNSSECStatus, error) {
+	txts, err := net.LookupTXT(name)
+	return txts, "", err
+}
+
+func (systemResolver) LookupNS(name string) ([]*net.NS, DNSSECStatus, error) {
+	nss, err := net.LookupNS(name)
+	return nss, "", err
+}
+
+func (systemResolver) LookupAAAA(name string) ([]net.IP, DNSSECStatus, error) {
+	ips, err := net.LookupIP(name)
+	if err != nil {
+		return nil, "", err
+	}
+	var v6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() == nil {
+			v6 = append(v6, ip)
+		}
+	}
+	return v6, "", nil
+}
+
+// LookupCAA and LookupSRV have no stdlib equivalent, so even the "plain"
+// system resolver issues a raw query (via a throwaway, non-DNSSEC
+// dnsResolver against the host's configured nameserver) to get them.
+func (systemResolver) LookupCAA(name string) ([]CAARecord, DNSSECStatus, error) {
+	return systemRawResolver().LookupCAA(name)
+}
+
+func (systemResolver) LookupSRV(name string) ([]*net.SRV, DNSSECStatus, error) {
+	return systemRawResolver().LookupSRV(name)
+}
+
+func systemRawResolver() *dnsResolver {
+	server := defaultServerFromResolvConf()
+	return &dnsResolver{server: server, network: "udp", client: &dns.Client{Net: "udp"}}
+}
+
+// dnsResolver issues queries against a single, explicitly chosen nameserver
+// using github.com/miekg/dns, optionally requesting DNSSEC validation (the
+// DO bit via SetEdns0) so the authoritative/recursive answer's Secure /
+// Insecure / Bogus state can be reported alongside the usual OK/Error.
+type dnsResolver struct {
+	server  string // host:port, e.g. "8.8.8.8:53"
+	network string // "udp" or "tcp"
+	dnssec  bool
+	client  *dns.Client
+}
+
+// newResolver builds the Resolver to use for the whole run. server may be
+// empty (use the system's configured resolver(s)), "host:port", or
+// "tcp://host:port" to force TCP. When both server and dnssec are unset the
+// stdlib-backed systemResolver is used so default behaviour is unchanged.
+func newResolver(server string, dnssec bool) Resolver {
+	if server == "" && !dnssec {
+		return systemResolver{}
+	}
+
+	network := "udp"
+	if strings.HasPrefix(server, "tcp://") {
+		network = "tcp"
+		server = strings.TrimPrefix(server, "tcp://")
+	} else if strings.HasPrefix(server, "udp://") {
+		server = strings.TrimPrefix(server, "udp://")
+	}
+
+	if server == "" {
+		server = defaultServerFromResolvConf()
+	} else if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return &dnsResolver{
+		server:  server,
+		network: network,
+		dnssec:  dnssec,
+		client:  &dns.Client{Net: network},
+	}
+}
+
+// defaultServerFromResolvConf reads the system's /etc/resolv.conf so
+// --dnssec can be used without also requiring --server, falling back to a
+// public resolver when resolv.conf can't be read (e.g. non-Unix hosts).
+func defaultServerFromResolvConf() string {
+	conf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(conf.Servers) == 0 {
+		return "8.8.8.8:53"
+	}
+	return net.JoinHostPort(conf.Servers[0], conf.Port)
+}
+
+// exchange sends a single query for name/qtype and classifies the DNSSEC
+// status of the reply when validation was requested.
+func (r *dnsResolver) exchange(name string, qtype uint16) (*dns.Msg, DNSSECStatus, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+	m.RecursionDesired = true
+	if r.dnssec {
+		m.SetEdns0(4096, true) // 4096 bufsize + DO bit
+	}
+
+	in, _, err := r.client.Exchange(m, r.server)
+	if err != nil {
+		return nil, "", err
+	}
+	if in.Rcode != dns.RcodeSuccess {
+		if r.dnssec && in.Rcode == dns.RcodeServerFailure {
+			return in, StatusBogus, nil
+		}
+		return in, "", fmt.Errorf("server returned %s", dns.RcodeToString[in.Rcode])
+	}
+
+	if !r.dnssec {
+		return in, "", nil
+	}
+	if in.AuthenticatedData {
+		return in, StatusSecure, nil
+	}
+	return in, StatusInsecure, nil
+}
+
+func (r *dnsResolver) LookupA(name string) ([]net.IP, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeA)
+	if err != nil {
+		return nil, status, err
+	}
+	var ips []net.IP
+	for _, rr := range in.Answer {
+		if a, ok := rr.(*dns.A); ok {
+			ips = append(ips, a.A)
+		}
+	}
+	return ips, status, nil
+}
+
+func (r *dnsResolver) LookupCNAME(name string) (string, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeCNAME)
+	if err != nil {
+		return "", status, err
+	}
+	for _, rr := range in.Answer {
+		if c, ok := rr.(*dns.CNAME); ok {
+			return c.Target, status, nil
+		}
+	}
+	return "", status, fmt.Errorf("no CNAME record found for %s", name)
+}
+
+func (r *dnsResolver) LookupMX(name string) ([]*net.MX, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeMX)
+	if err != nil {
+		return nil, status, err
+	}
+	var mxs []*net.MX
+	for _, rr := range in.Answer {
+		if mx, ok := rr.(*dns.MX); ok {
+			mxs = append(mxs, &net.MX{Host: mx.Mx, Pref: mx.Preference})
+		}
+	}
+	return mxs, status, nil
+}
+
+func (r *dnsResolver) LookupTXT(name string) ([]string, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeTXT)
+	if err != nil {
+		return nil, status, err
+	}
+	var txts []string
+	for _, rr := range in.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			txts = append(txts, strings.Join(txt.Txt, ""))
+		}
+	}
+	return txts, status, nil
+}
+
+func (r *dnsResolver) LookupNS(name string) ([]*net.NS, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeNS)
+	if err != nil {
+		return nil, status, err
+	}
+	var nss []*net.NS
+	for _, rr := range in.Answer {
+		if ns, ok := rr.(*dns.NS); ok {
+			nss = append(nss, &net.NS{Host: ns.Ns})
+		}
+	}
+	return nss, status, nil
+}
+
+func (r *dnsResolver) LookupAAAA(name string) ([]net.IP, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeAAAA)
+	if err != nil {
+		return nil, status, err
+	}
+	var ips []net.IP
+	for _, rr := range in.Answer {
+		if aaaa, ok := rr.(*dns.AAAA); ok {
+			ips = append(ips, aaaa.AAAA)
+		}
+	}
+	return ips, status, nil
+}
+
+func (r *dnsResolver) LookupCAA(name string) ([]CAARecord, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeCAA)
+	if err != nil {
+		return nil, status, err
+	}
+	var caas []CAARecord
+	for _, rr := range in.Answer {
+		if caa, ok := rr.(*dns.CAA); ok {
+			caas = append(caas, CAARecord{Flag: caa.Flag, Tag: caa.Tag, Value: caa.Value})
+		}
+	}
+	return caas, status, nil
+}
+
+func (r *dnsResolver) LookupSRV(name string) ([]*net.SRV, DNSSECStatus, error) {
+	in, status, err := r.exchange(name, dns.TypeSRV)
+	if err != nil {
+		return nil, status, err
+	}
+	var srvs []*net.SRV
+	for _, rr := range in.Answer {
+		if srv, ok := rr.(*dns.SRV); ok {
+			srvs = append(srvs, &net.SRV{Target: srv.Target, Port: srv.Port, Priority: srv.Priority, Weight: srv.Weight})
+		}
+	}
+	return srvs, status, nil
+}
+
+// writeDNSSECStatus appends a "DNSSEC : ..." line to out when status was
+// actually reported (i.e. --dnssec was requested for this run).
+func writeDNSSECStatus(out *strings.Builder, status DNSSECStatus) {
+	if status == "" {
+		return
+	}
+	switch status {
+	case StatusSecure:
+		out.WriteString(color.GreenString("DNSSEC : %s\n", status))
+	case StatusBogus:
+		out.WriteString(color.RedString("DNSSEC : %s\n", status))
+	default:
+		out.WriteString(color.YellowString("DNSSEC : %s\n", status))
+	}
+}
+
+// namedResolver pairs a Resolver with the label used in comparison output,
+// e.g. "ns1", "ns2" for authoritative servers or the raw "8.8.8.8:53" a user
+// passed via --compare-resolvers.
+type namedResolver struct {
+	Name     string
+	Resolver Resolver
+}
+
+// authoritativeResolvers looks up the domain's own NS records and returns one
+// named resolver per authoritative nameserver, so propagation/mismatch
+// checks can be run directly against the source of truth instead of a
+// recursive resolver that may still be serving a cached answer.
+func authoritativeResolvers(domain string, dnssec bool) ([]namedResolver, error) {
+	nss, err := net.LookupNS(domain)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up authoritative NS for %s: %w", domain, err)
+	}
+
+	var resolvers []namedResolver
+	for _, ns := range nss {
+		host := strings.TrimSuffix(ns.Host, ".")
+		resolvers = append(resolvers, namedResolver{
+			Name:     host,
+			Resolver: newResolver(net.JoinHostPort(host, "53"), dnssec),
+		})
+	}
+	return resolvers, nil
+}
+
+// compareResolvers parses the --compare-resolvers value, a comma-separated
+// list of "host:port" servers and/or the literal "auth" (expanded to every
+// authoritative NS for domain via authoritativeResolvers).
+func compareResolvers(domain string, spec string, dnssec bool) ([]namedResolver, error) {
+	var resolvers []namedResolver
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if item == "auth" {
+			auth, err := authoritativeResolvers(domain, dnssec)
+			if err != nil {
+				return nil, err
+			}
+			resolvers = append(resolvers, auth...)
+			continue
+		}
+		resolvers = append(resolvers, namedResolver{
+			Name:     item,
+			Resolver: newResolver(item, dnssec),
+		})
+	}
+	return resolvers, nil
+}
+
+// compareRecord runs a single config line against every resolver and renders
+// the bracketed per-resolver matrix; processLine prints it after the config
+// fields, giving a row such as:
+//   a www 10.0.0.1  [ns1:OK ns2:OK 8.8.8.8:MISMATCH(10.0.0.2)]
+func compareRecord(resolvers []namedResolver, recordType string, dnshost string, confvalue string, domain string, configParts []string) (string, bool) {
+	var parts []string
+	allOK := true
+
+	for _, nr := range resolvers {
+		ok, value, err := lookupOneResolver(nr.Resolver, recordType, dnshost, confvalue, domain, configParts)
+		switch {
+		case err != nil:
+			parts = append(parts, fmt.Sprintf("%s:ERROR(%v)", nr.Name, err))
+			allOK = false
+		case ok:
+			parts = append(parts, fmt.Sprintf("%s:OK", nr.Name))
+		default:
+			parts = append(parts, fmt.Sprintf("%s:MISMATCH(%s)", nr.Name, value))
+			allOK = false
+		}
+	}
+
+	return "[" + strings.Join(parts, " ") + "]", allOK
+}
+
+// lookupOneResolver performs the normalization + comparison that TestA/
+// TestCNAME/TestMX/TestTXT/TestNS do for a single resolver, but returns a
+// plain (matched, actual value) pair instead of pre-formatted, colored text
+// so compareRecord can run it once per resolver in the matrix. Its "txt"
+// case dispatches DMARC/DKIM/SPF lines through the same tag-aware matching
+// TestDMARC/TestDKIM/TestTXT use, rather than a plain string comparison.
+func lookupOneResolver(resolver Resolver, recordType string, dnshost string, confvalue string, domain string, configParts []string) (bool, string, error) {
+	switch recordType {
+	case "a":
+		lookupvalue := dnshost
+		if strings.HasPrefix(lookupvalue, "www.") {
+			lookupvalue = lookupvalue + "." + domain
+		} else {
+			lookupvalue = AdjustHostname(strings.TrimSuffix(lookupvalue, "."), domain)
+		}
+		ips, _, err := resolver.LookupA(lookupvalue)
+		if err != nil {
+			return false, "", err
+		}
+		for _, ip := range ips {
+			if ip.String() == confvalue {
+				return true, ip.String(), nil
+			}
+		}
+		if len(ips) > 0 {
+			return false, ips[0].String(), nil
+		}
+		return false, "", nil
+
+	case "cname":
+		lookupvalue := dnshost
+		if strings.Contains(lookupvalue, domain) {
+			lookupvalue = strings.TrimSuffix(lookupvalue, ".")
+		} else {
+			lookupvalue = AdjustHostname(strings.TrimSuffix(lookupvalue, "."), domain)
+		}
+		confvalue = strings.TrimSuffix(confvalue, ".")
+		cname, _, err := resolver.LookupCNAME(lookupvalue)
+		if err != nil {
+			return false, "", err
+		}
+		return strings.Contains(cname, confvalue), cname, nil
+
+	case "ns":
+		lookupvalue := dnshost
+		if strings.Contains(lookupvalue, domain) {
+			lookupvalue = strings.TrimSuffix(lookupvalue, ".")
+		} else {
+			lookupvalue = AdjustHostname(strings.TrimSuffix(lookupvalue, "."), domain)
+		}
+		confvalue = strings.TrimSuffix(confvalue, ".")
+		nss, _, err := resolver.LookupNS(lookupvalue)
+		if err != nil {
+			return false, "", err
+		}
+		for _, ns := range nss {
+			if strings.Contains(ns.Host, confvalue) {
+				return true, ns.Host, nil
+			}
+		}
+		if len(nss) > 0 {
+			return false, nss[0].Host, nil
+		}
+		return false, "", nil
+
+	case "mx":
+		lookupvalue := domain
+		if len(configParts) == 4 {
+			lookupvalue = AdjustHostname(strings.TrimSuffix(configParts[3], "."), domain)
+		}
+		// Same field choice as TestMX: the mail server is the second field,
+		// except for "mx @ smtp-server" where it is the third.
+		mxconf := strings.TrimSuffix(dnshost, ".")
+		if len(configParts) == 3 && dnshost == "@" {
+			mxconf = strings.TrimSuffix(confvalue, ".")
+		}
+		mxs, _, err := resolver.LookupMX(lookupvalue)
+		if err != nil {
+			return false, "", err
+		}
+		for _, mx := range mxs {
+			if strings.TrimSuffix(strings.ToLower(mx.Host), ".") == strings.ToLower(mxconf) {
+				return true, mx.Host, nil
+			}
+		}
+		if len(mxs) > 0 {
+			return false, mxs[0].Host, nil
+		}
+		return false, "", nil
+
+	case "txt":
+		// Full value may be split across extra fields, same as TestDMARC/
+		// TestDKIM/TestTXT.
+		wantvalue := confvalue
+		if 3 <= len(configParts) {
+			for i := 3; i < len(configParts); i++ {
+				wantvalue += " " + configParts[i]
+			}
+		}
+
+		switch {
+		case strings.Contains(dnshost, "domainkey"):
+			lookupvalue := AdjustHostname(strings.TrimSuffix(dnshost, "."), domain)
+			txts, _, err := resolver.LookupTXT(lookupvalue)
+			if err != nil {
+				return false, "", err
+			}
+			record := findDKIMRecord(txts)
+			if record == "" {
+				return false, "", nil
+			}
+			return strings.Contains(record, wantvalue), record, nil
+
+		case strings.Contains(dnshost, "dmarc"):
+			lookupvalue := AdjustHostname(strings.TrimSuffix(dnshost, "."), domain)
+			txts, _, err := resolver.LookupTXT(lookupvalue)
+			if err != nil {
+				return false, "", err
+			}
+			record := findDMARCRecord(txts)
+			if record == "" {
+				return false, "", nil
+			}
+			return dmarcMatches(record, wantvalue), record, nil
+
+		case strings.HasPrefix(strings.TrimSpace(wantvalue), "v=spf1"):
+			lookupvalue := AdjustHostname(strings.TrimSuffix(dnshost, "."), domain)
+			txts, _, err := resolver.LookupTXT(lookupvalue)
+			if err != nil {
+				return false, "", err
+			}
+			record := findSPFRecord(txts)
+			if record == "" {
+				return false, "", nil
+			}
+			ips, _, err := expandSPF(resolver, record, lookupvalue)
+			if err != nil {
+				return false, record, err
+			}
+			return true, strings.Join(ips, ", "), nil
+
+		default:
+			// Same as TestTXT: "@" compares the first value field only, other
+			// hosts the joined value; a host already containing domain is
+			// looked up without its trailing dot.
+			lookupvalue := AdjustHostname(dnshost, domain)
+			want := wantvalue
+			if dnshost == "@" {
+				want = confvalue
+			} else if strings.Contains(dnshost, domain) {
+				lookupvalue = strings.TrimSuffix(dnshost, ".")
+			}
+			txts, _, err := resolver.LookupTXT(lookupvalue)
+			if err != nil {
+				return false, "", err
+			}
+			for _, txt := range txts {
+				if txt == want {
+					return true, txt, nil
+				}
+			}
+			if len(txts) > 0 {
+				return false, txts[0], nil
+			}
+			return false, "", nil
+		}
+
+	default:
+		return false, "", fmt.Errorf("unsupported record type for comparison: %s", recordType)
+	}
+}
+
 func PrintHelp() {
 	str := `## dnschecker for Value-domain
 Value-domainの「簡易DNS設定」で設定した値が、
@@ -50,11 +610,19 @@ Value-domainの「簡易DNS設定」で設定した値が、
 
 使用例：
 ./dnschecker my-domain.com dns-mydomain.conf
+
+オプション：
+--server <host:port|tcp://host:port>  ルックアップに使うDNSサーバーを指定します
+--dnssec                              DNSSEC検証(DO bit)を要求し、Secure/Insecure/Bogusを表示します
+--authoritative                       ドメインの権威NSを調べ、各NSに対して直接問い合わせます
+--compare-resolvers <server1,server2,auth>  複数のリゾルバで同じレコードを調べ、一致/不一致を表示します
+--format <auto|value|bind>            設定ファイルの形式を指定します(デフォルトは自動判定)
+-p                                    各行のチェックを並列実行します
 `
 	fmt.Println(str)
 }
 
-func TestA(lookupvalue string, confvalue string, domain string) (string, bool) {
+func TestA(resolver Resolver, lookupvalue string, confvalue string, domain string) (string, bool, DNSSECStatus) {
 	var out strings.Builder
 	// 設定の書式
 	// a * IP  <skipped already>
@@ -78,12 +646,12 @@ func TestA(lookupvalue string, confvalue string, domain string) (string, bool) {
 	}
 
 	// DNSルックアップを実行
-	iprecords, err := net.LookupIP(lookupvalue)
+	iprecords, status, err := resolver.LookupA(lookupvalue)
 	if err != nil {
 		out.WriteString(color.RedString("Error\n"))
 		out.WriteString(fmt.Sprintf("DNS lookup failed for A record: %v\n", err))
 		out.WriteString("\n")
-		return out.String(), false
+		return out.String(), false, status
 	}
 
 	// 結果を表示
@@ -103,12 +671,13 @@ func TestA(lookupvalue string, confvalue string, domain string) (string, bool) {
 	} else {
 		out.WriteString(color.RedString("Error\n"))
 	}
+	writeDNSSECStatus(&out, status)
 	out.WriteString("\n")
 
-	return out.String(), flag
+	return out.String(), flag, status
 }
 
-func TestCNAME(lookupvalue string, confvalue string, domain string) (string, bool) {
+func TestCNAME(resolver Resolver, lookupvalue string, confvalue string, domain string) (string, bool, DNSSECStatus) {
 	var out strings.Builder
 	// 設定の書式
 	// cname host FQDN
@@ -129,12 +698,12 @@ func TestCNAME(lookupvalue string, confvalue string, domain string) (string, boo
 	confvalue = strings.TrimSuffix(confvalue, ".")
 
 	// DNSルックアップを実行
-	cname, err := net.LookupCNAME(lookupvalue)
+	cname, status, err := resolver.LookupCNAME(lookupvalue)
 	if err != nil {
 		out.WriteString(color.RedString("Error\n"))
 		out.WriteString(fmt.Sprintf("DNS lookup failed for CNAME record: %v\n", err))
 		out.WriteString("\n")
-		return out.String(), false
+		return out.String(), false, status
 	}
 
 	// 結果を表示
@@ -152,12 +721,13 @@ func TestCNAME(lookupvalue string, confvalue string, domain string) (string, boo
 	} else {
 		out.WriteString(color.RedString("Error\n"))
 	}
+	writeDNSSECStatus(&out, status)
 	out.WriteString("\n")
 
-	return out.String(), flag
+	return out.String(), flag, status
 }
 
-func TestMX(inputHost string, inputValue string, domain string, configParts []string) (string, bool) {
+func TestMX(resolver Resolver, inputHost string, inputValue string, domain string, configParts []string) (string, bool, DNSSECStatus) {
 	var out strings.Builder
 	// 設定の書式
 	// mx @ smtp-server(.)
@@ -186,22 +756,633 @@ func TestMX(inputHost string, inputValue string, domain string, configParts []st
 	}
 
 	// DNSルックアップを実行
-	mxrecords, err := net.LookupMX(lookupvalue)
+	mxrecords, status, err := resolver.LookupMX(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for MX record: %v\n", err))
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
+
+	// 結果を表示
+	out.WriteString("Type   : mx\n")
+	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
+
+	// 一致するMXレコードを探す
+	for _, mx := range mxrecords {
+		if strings.TrimSuffix(strings.ToLower(mx.Host), ".") == strings.ToLower(confvalue) {
+			out.WriteString(fmt.Sprintf("Value  : %s\n", strings.TrimSuffix(mx.Host, ".")))
+			flag = true
+		}
+	}
+
+	if flag {
+		out.WriteString(color.GreenString("OK\n"))
+	} else {
+		out.WriteString(color.RedString("Error\n"))
+	}
+	writeDNSSECStatus(&out, status)
+	out.WriteString("\n")
+
+	return out.String(), flag, status
+}
+
+// spfLookupLimit is RFC 7208's cap on the number of DNS lookups an SPF
+// evaluation may perform (include/redirect/a/mx/exists, each counts once;
+// ip4/ip6/all do not).
+const spfLookupLimit = 10
+
+// errSPFPermError marks an SPF evaluation that blew the lookup budget; it is
+// reported as Untested rather than Error since the record itself may be
+// perfectly well-formed.
+var errSPFPermError = errors.New("SPF permerror, >10 lookups")
+
+// findSPFRecord returns the first TXT record that looks like an SPF policy,
+// or "" if none of the records do (a domain may have other unrelated TXT
+// records alongside its SPF one).
+func findSPFRecord(txts []string) string {
+	for _, txt := range txts {
+		if strings.HasPrefix(strings.TrimSpace(txt), "v=spf1") {
+			return txt
+		}
+	}
+	return ""
+}
+
+// expandSPF flattens an SPF record's include/redirect/a/mx/exists
+// mechanisms into the effective set of sender IPs/mechanisms, enforcing the
+// RFC 7208 lookup budget and loop protection along the way.
+func expandSPF(resolver Resolver, record string, domain string) ([]string, int, error) {
+	lookups := 0
+	visited := map[string]bool{domain: true}
+	ips, err := expandSPFRecord(resolver, record, domain, visited, &lookups)
+	return ips, lookups, err
+}
+
+func expandSPFRecord(resolver Resolver, record string, domain string, visited map[string]bool, lookups *int) ([]string, error) {
+	var ips []string
+
+	for _, field := range strings.Fields(record) {
+		switch {
+		case field == "v=spf1":
+			continue
+
+		case strings.HasPrefix(field, "ip4:"):
+			ips = append(ips, strings.TrimPrefix(field, "ip4:"))
+
+		case strings.HasPrefix(field, "ip6:"):
+			ips = append(ips, strings.TrimPrefix(field, "ip6:"))
+
+		case strings.HasPrefix(field, "include:"):
+			target := strings.TrimPrefix(field, "include:")
+			subIPs, err := expandSPFReference(resolver, "include", target, visited, lookups)
+			if err != nil {
+				return ips, err
+			}
+			ips = append(ips, subIPs...)
+
+		case strings.HasPrefix(field, "redirect="):
+			target := strings.TrimPrefix(field, "redirect=")
+			subIPs, err := expandSPFReference(resolver, "redirect", target, visited, lookups)
+			if err != nil {
+				return ips, err
+			}
+			ips = append(ips, subIPs...)
+
+		case field == "a" || strings.HasPrefix(field, "a:") || strings.HasPrefix(field, "a/"):
+			target := domain
+			if strings.HasPrefix(field, "a:") {
+				target = strings.SplitN(strings.TrimPrefix(field, "a:"), "/", 2)[0]
+			}
+			if err := countSPFLookup(lookups); err != nil {
+				return ips, err
+			}
+			addrs, _, err := resolver.LookupA(target)
+			if err != nil {
+				return ips, fmt.Errorf("a:%s: %w", target, err)
+			}
+			for _, ip := range addrs {
+				ips = append(ips, ip.String())
+			}
+
+		case field == "mx" || strings.HasPrefix(field, "mx:") || strings.HasPrefix(field, "mx/"):
+			target := domain
+			if strings.HasPrefix(field, "mx:") {
+				target = strings.SplitN(strings.TrimPrefix(field, "mx:"), "/", 2)[0]
+			}
+			if err := countSPFLookup(lookups); err != nil {
+				return ips, err
+			}
+			mxs, _, err := resolver.LookupMX(target)
+			if err != nil {
+				return ips, fmt.Errorf("mx:%s: %w", target, err)
+			}
+			for _, mx := range mxs {
+				addrs, _, err := resolver.LookupA(mx.Host)
+				if err != nil {
+					continue
+				}
+				for _, ip := range addrs {
+					ips = append(ips, ip.String())
+				}
+			}
+
+		case strings.HasPrefix(field, "exists:"):
+			target := strings.TrimPrefix(field, "exists:")
+			if err := countSPFLookup(lookups); err != nil {
+				return ips, err
+			}
+			if _, _, err := resolver.LookupA(target); err == nil {
+				ips = append(ips, target)
+			}
+
+		default:
+			// qualifiers (+/-/~/?all) and unsupported mechanisms (ptr, exp=, ...)
+			// don't contribute sender IPs and aren't counted as lookups
+		}
+	}
+
+	return ips, nil
+}
+
+// expandSPFReference resolves an include:/redirect= target's own SPF
+// record and recurses into it, counting the lookup and guarding against
+// include loops via the shared visited set.
+func expandSPFReference(resolver Resolver, mechanism string, target string, visited map[string]bool, lookups *int) ([]string, error) {
+	if err := countSPFLookup(lookups); err != nil {
+		return nil, err
+	}
+	if visited[target] {
+		return nil, fmt.Errorf("%s:%s: loop detected", mechanism, target)
+	}
+	visited[target] = true
+
+	txts, _, err := resolver.LookupTXT(target)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s: %w", mechanism, target, err)
+	}
+	record := findSPFRecord(txts)
+	if record == "" {
+		return nil, fmt.Errorf("%s:%s: no v=spf1 TXT record found", mechanism, target)
+	}
+	return expandSPFRecord(resolver, record, target, visited, lookups)
+}
+
+func countSPFLookup(lookups *int) error {
+	*lookups++
+	if *lookups > spfLookupLimit {
+		return errSPFPermError
+	}
+	return nil
+}
+
+// TestTXT returns an extra untested count (beyond the usual output/ok/status)
+// because an SPF record that blows the RFC 7208 10-lookup budget is neither
+// a clean OK nor a firm Error - it's reported as Untested, same as a wildcard.
+func TestTXT(resolver Resolver, inputHost string, inputValue string, domain string, configParts []string) (string, bool, DNSSECStatus, int) {
+	var out strings.Builder
+	// 設定の書式
+	// txt @ v=spf1 array of values
+	// txt @ IP
+	// txt host(.) value
+	// txt host(.) v=spf1 value
+	// txt value
+	// (x._domainkey と _dmarc は processLine から TestDKIM/TestDMARC へ直接振り分けられる)
+
+	flag := false
+	var lookupvalue string
+	var confvalue string
+
+	// TXTレコードの様々なパターンを処理
+	if inputHost == "@" {
+		if strings.Contains(inputValue, "v=spf1") {
+			// case : txt @ v=spf1 value
+			lookupvalue = AdjustHostname(inputHost, domain)
+
+			// SPF値の場合は特別な処理：全ての値を結合して設定値とする
+			confvalue = inputValue // まず最初の値を設定
+			if 3 <= len(configParts) {
+				for i := 3; i < len(configParts); i++ {
+					confvalue += " " + configParts[i]
+				}
+			}
+		} else {
+			// case : txt @ IP
+			lookupvalue = AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
+			confvalue = inputValue
+		}
+	} else if strings.Contains(inputHost, domain) {
+		// case : txt host(.) value
+		lookupvalue = strings.TrimSuffix(inputHost, ".")
+		confvalue = inputValue // 最初の値を設定
+		if 3 <= len(configParts) {
+			for i := 3; i < len(configParts); i++ {
+				confvalue += " " + configParts[i]
+			}
+		}
+	} else if strings.Contains(inputValue, "v=spf1") {
+		// case : txt host(.) v=spf1 value
+		if strings.Contains(inputHost, domain) {
+			lookupvalue = strings.TrimSuffix(inputHost, ".")
+		} else {
+			lookupvalue = AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
+		}
+		confvalue = inputValue // 最初の値を設定
+		if 3 <= len(configParts) {
+			for i := 3; i < len(configParts); i++ {
+				confvalue += " " + configParts[i]
+			}
+		}
+	} else {
+		// _dmarc と x._domainkey は TestDMARC/TestDKIM が専用に処理するため、
+		// ここに到達するのはそれ以外の txt 設定のみ
+		// case : txt value
+		lookupvalue = AdjustHostname(inputHost, domain)
+		confvalue = inputValue // 最初の値を設定
+		if 3 <= len(configParts) {
+			for i := 3; i < len(configParts); i++ {
+				confvalue += " " + configParts[i]
+			}
+		}
+	}
+
+	// DNSルックアップを実行
+	txtrecords, status, err := resolver.LookupTXT(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for TXT record: %v\n", err))
+		out.WriteString("\n")
+		return out.String(), false, status, 0
+	}
+
+	// 結果を表示
+	out.WriteString("Type   : txt\n")
+	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
+
+	// SPFレコードの場合は、単純な文字列比較ではなく include/redirect を再帰的に
+	// 展開し、有効な送信元IPの集合とRFC 7208のルックアップ制限(10回)を検証する
+	if strings.HasPrefix(strings.TrimSpace(confvalue), "v=spf1") {
+		record := findSPFRecord(txtrecords)
+		if record == "" {
+			out.WriteString(color.RedString("Error\n"))
+			out.WriteString("no v=spf1 TXT record found\n")
+			writeDNSSECStatus(&out, status)
+			out.WriteString("\n")
+			return out.String(), false, status, 0
+		}
+		out.WriteString(fmt.Sprintf("Value  : %s\n", record))
+
+		ips, lookups, err := expandSPF(resolver, record, lookupvalue)
+		if errors.Is(err, errSPFPermError) {
+			out.WriteString(color.YellowString("Untested : SPF permerror, >10 lookups\n"))
+			writeDNSSECStatus(&out, status)
+			out.WriteString("\n")
+			return out.String(), false, status, 1
+		}
+		out.WriteString(fmt.Sprintf("Lookups: %d/%d\n", lookups, spfLookupLimit))
+		if err != nil {
+			out.WriteString(color.RedString("Error\n"))
+			out.WriteString(fmt.Sprintf("SPF mechanism failed: %v\n", err))
+			writeDNSSECStatus(&out, status)
+			out.WriteString("\n")
+			return out.String(), false, status, 0
+		}
+		out.WriteString(fmt.Sprintf("Senders: %s\n", strings.Join(ips, ", ")))
+		out.WriteString(color.GreenString("OK\n"))
+		writeDNSSECStatus(&out, status)
+		out.WriteString("\n")
+		return out.String(), true, status, 0
+	}
+
+	// 一致するTXTレコードを探す
+	for _, txt := range txtrecords {
+
+		if txt == confvalue {
+			out.WriteString(fmt.Sprintf("Value  : %s\n", txt))
+			flag = true
+		}
+	}
+
+	if flag {
+		out.WriteString(color.GreenString("OK\n"))
+	} else {
+		out.WriteString(color.RedString("Error\n"))
+	}
+	writeDNSSECStatus(&out, status)
+	out.WriteString("\n")
+
+	return out.String(), flag, status, 0
+}
+
+// parseTagValues splits a "tag1=value1; tag2=value2" DNS record (the syntax
+// shared by SPF, DMARC and DKIM) into a tag -> value map.
+func parseTagValues(record string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(record, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		tags[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return tags
+}
+
+// findDMARCRecord returns the v=DMARC1 TXT record among a set of TXT
+// answers, or "" if none is present.
+func findDMARCRecord(txtrecords []string) string {
+	for _, txt := range txtrecords {
+		if strings.HasPrefix(strings.TrimSpace(txt), "v=DMARC1") {
+			return txt
+		}
+	}
+	return ""
+}
+
+// dmarcMatches reports whether a DMARC record's tags satisfy confvalue's
+// tags, tolerating tag reordering and extra tags the config doesn't care
+// about - shared by TestDMARC and the multi-resolver comparison path so
+// both agree on what counts as a match.
+func dmarcMatches(record string, confvalue string) bool {
+	gotTags := parseTagValues(record)
+	wantTags := parseTagValues(confvalue)
+
+	flag := gotTags["v"] == "DMARC1"
+	for tag, want := range wantTags {
+		if tag == "v" {
+			continue
+		}
+		if gotTags[tag] != want {
+			flag = false
+		}
+	}
+	return flag
+}
+
+// findDKIMRecord returns the selector TXT record that carries a DKIM
+// public key (i.e. contains a "p=" tag), or "" if none is present.
+func findDKIMRecord(txtrecords []string) string {
+	for _, txt := range txtrecords {
+		if strings.Contains(txt, "p=") {
+			return txt
+		}
+	}
+	return ""
+}
+
+// TestDMARC parses the `_dmarc` TXT record's tag/value pairs and compares
+// them semantically against the config (so a provider reordering tags or
+// adding tags the config doesn't list doesn't look like a mismatch; every
+// tag the config does list, rua/ruf included, must match exactly), then warns about
+// policy choices that weaken enforcement: p=none, pct<100, or rua/ruf
+// pointing at a third-party domain that hasn't authorized receiving this
+// domain's reports via a `_report._dmarc` record (RFC 7489 section 7.1).
+func TestDMARC(resolver Resolver, inputHost string, inputValue string, domain string, configParts []string) (string, bool, DNSSECStatus, int) {
+	var out strings.Builder
+
+	lookupvalue := AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
+
+	confvalue := inputValue
+	if 3 <= len(configParts) {
+		for i := 3; i < len(configParts); i++ {
+			confvalue += " " + configParts[i]
+		}
+	}
+
+	txtrecords, status, err := resolver.LookupTXT(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for DMARC record: %v\n", err))
+		out.WriteString("\n")
+		return out.String(), false, status, 0
+	}
+
+	out.WriteString("Type   : dmarc\n")
+	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
+
+	record := findDMARCRecord(txtrecords)
+	if record == "" {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString("no v=DMARC1 TXT record found\n")
+		writeDNSSECStatus(&out, status)
+		out.WriteString("\n")
+		return out.String(), false, status, 0
+	}
+	out.WriteString(fmt.Sprintf("Value  : %s\n", record))
+
+	flag := dmarcMatches(record, confvalue)
+	gotTags := parseTagValues(record)
+
+	var warnings []string
+	if gotTags["p"] == "none" {
+		warnings = append(warnings, "p=none, policy is monitor-only and not enforcing")
+	}
+	if pctStr, ok := gotTags["pct"]; ok {
+		if pct, err := strconv.Atoi(pctStr); err == nil && pct < 100 {
+			warnings = append(warnings, fmt.Sprintf("pct=%d, policy only applied to a subset of mail", pct))
+		}
+	}
+	for _, tag := range []string{"rua", "ruf"} {
+		uris, ok := gotTags[tag]
+		if !ok {
+			continue
+		}
+		for _, uri := range strings.Split(uris, ",") {
+			uri = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(uri), "mailto:"))
+			at := strings.LastIndex(uri, "@")
+			if at < 0 {
+				continue
+			}
+			reportDomain := uri[at+1:]
+			if strings.EqualFold(reportDomain, domain) || strings.HasSuffix(strings.ToLower(reportDomain), "."+strings.ToLower(domain)) {
+				continue // reports to our own domain never need authorizing
+			}
+			authName := domain + "._report._dmarc." + reportDomain
+			if _, _, err := resolver.LookupTXT(authName); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s=%s is external and missing the %s authorization record", tag, reportDomain, authName))
+			}
+		}
+	}
+
+	for _, w := range warnings {
+		out.WriteString(color.YellowString("Warning: %s\n", w))
+	}
+
+	if flag {
+		out.WriteString(color.GreenString("OK\n"))
+	} else {
+		out.WriteString(color.RedString("Error\n"))
+	}
+	writeDNSSECStatus(&out, status)
+	out.WriteString("\n")
+
+	return out.String(), flag, status, len(warnings)
+}
+
+// TestDKIM parses the selector TXT record's `v=DKIM1; k=rsa; p=...` tags,
+// base64-decodes the public key and checks it's a well-formed RSA key of at
+// least 1024 bits, and flags an empty p= (a deliberately revoked key) as a
+// failure rather than treating it as just another string mismatch.
+func TestDKIM(resolver Resolver, inputHost string, inputValue string, domain string, configParts []string) (string, bool, DNSSECStatus, int) {
+	var out strings.Builder
+
+	lookupvalue := AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
+	confvalue := inputValue
+	if 3 <= len(configParts) {
+		for i := 3; i < len(configParts); i++ {
+			confvalue += " " + configParts[i]
+		}
+	}
+
+	txtrecords, status, err := resolver.LookupTXT(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for DKIM record: %v\n", err))
+		out.WriteString("\n")
+		return out.String(), false, status, 0
+	}
+
+	out.WriteString("Type   : dkim\n")
+	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
+
+	record := findDKIMRecord(txtrecords)
+	if record == "" {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString("no DKIM TXT record found\n")
+		writeDNSSECStatus(&out, status)
+		out.WriteString("\n")
+		return out.String(), false, status, 0
+	}
+	out.WriteString(fmt.Sprintf("Value  : %s\n", record))
+
+	tags := parseTagValues(record)
+	pubkey, hasP := tags["p"]
+
+	var warnings []string
+	flag := strings.Contains(record, confvalue)
+
+	switch {
+	case !hasP:
+		warnings = append(warnings, "no p= tag present")
+	case pubkey == "":
+		warnings = append(warnings, "DKIM key revoked (empty p=)")
+		flag = false
+	default:
+		keyBytes, err := base64.StdEncoding.DecodeString(pubkey)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("p= is not valid base64: %v", err))
+			flag = false
+		} else if pub, err := x509.ParsePKIXPublicKey(keyBytes); err != nil {
+			warnings = append(warnings, fmt.Sprintf("p= is not a well-formed public key: %v", err))
+			flag = false
+		} else if rsaKey, ok := pub.(*rsa.PublicKey); !ok {
+			warnings = append(warnings, "p= is not an RSA public key")
+		} else if bits := rsaKey.N.BitLen(); bits < 1024 {
+			warnings = append(warnings, fmt.Sprintf("RSA key is only %d bits (weak, want >= 1024)", bits))
+		}
+	}
+
+	for _, w := range warnings {
+		out.WriteString(color.YellowString("Warning: %s\n", w))
+	}
+
+	if flag {
+		out.WriteString(color.GreenString("OK\n"))
+	} else {
+		out.WriteString(color.RedString("Error\n"))
+	}
+	writeDNSSECStatus(&out, status)
+	out.WriteString("\n")
+
+	return out.String(), flag, status, len(warnings)
+}
+
+func TestNS(resolver Resolver, lookupvalue string, confvalue string, domain string) (string, bool, DNSSECStatus) {
+	var out strings.Builder
+	// 設定の書式
+	// ns subdomain host
+
+	flag := false
+
+	// 入力値を整形
+	if strings.Contains(lookupvalue, domain) {
+		// すでにドメインが含まれている場合はそのまま使用
+		lookupvalue = strings.TrimSuffix(lookupvalue, ".")
+	} else {
+		// ドメインが含まれていない場合は適切に調整
+		lookupvalue = AdjustHostname(strings.TrimSuffix(lookupvalue, "."), domain)
+	}
+
+	// 設定値から末尾のドットを削除
+	confvalue = strings.TrimSuffix(confvalue, ".")
+
+	// DNSルックアップを実行
+	nameserver, status, err := resolver.LookupNS(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for NS record: %v\n", err))
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
+
+	// 結果を表示
+	out.WriteString("Type   : ns\n")
+	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
+
+	// 一致するNSレコードを探す
+	for _, ns := range nameserver {
+		if strings.Contains(ns.Host, confvalue) {
+			out.WriteString(fmt.Sprintf("Value  : %s\n", ns.Host))
+			flag = true
+		}
+	}
+
+	if flag {
+		out.WriteString(color.GreenString("OK\n"))
+	} else {
+		out.WriteString(color.RedString("Error\n"))
+	}
+	writeDNSSECStatus(&out, status)
+	out.WriteString("\n")
+
+	return out.String(), flag, status
+}
+
+func TestAAAA(resolver Resolver, lookupvalue string, confvalue string, domain string) (string, bool, DNSSECStatus) {
+	var out strings.Builder
+	// 設定の書式
+	// aaaa host IPv6
+
+	flag := false
+
+	// 入力値を整形(TestAと同じwww.サブドメインの特別扱い)
+	if strings.HasPrefix(lookupvalue, "www.") {
+		lookupvalue = lookupvalue + "." + domain
+	} else {
+		lookupvalue = AdjustHostname(strings.TrimSuffix(lookupvalue, "."), domain)
+	}
+
+	// DNSルックアップを実行
+	iprecords, status, err := resolver.LookupAAAA(lookupvalue)
 	if err != nil {
 		out.WriteString(color.RedString("Error\n"))
-		out.WriteString(fmt.Sprintf("DNS lookup failed for MX record: %v\n", err))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for AAAA record: %v\n", err))
 		out.WriteString("\n")
-		return out.String(), false
+		return out.String(), false, status
 	}
 
 	// 結果を表示
-	out.WriteString("Type   : mx\n")
+	out.WriteString("Type   : aaaa\n")
 	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
 
-	// 一致するMXレコードを探す
-	for _, mx := range mxrecords {
-		if strings.TrimSuffix(strings.ToLower(mx.Host), ".") == strings.ToLower(confvalue) {
-			out.WriteString(fmt.Sprintf("Value  : %s\n", strings.TrimSuffix(mx.Host, ".")))
+	// 一致するIPv6アドレスを探す
+	for _, ip := range iprecords {
+		if ip.String() == confvalue {
+			out.WriteString(fmt.Sprintf("Value  : %s\n", ip.String()))
 			flag = true
 		}
 	}
@@ -211,113 +1392,57 @@ func TestMX(inputHost string, inputValue string, domain string, configParts []st
 	} else {
 		out.WriteString(color.RedString("Error\n"))
 	}
+	writeDNSSECStatus(&out, status)
 	out.WriteString("\n")
 
-	return out.String(), flag
+	return out.String(), flag, status
 }
 
-func TestTXT(inputHost string, inputValue string, domain string, configParts []string) (string, bool) {
+// TestCAA checks that a domain's CAA record(s) authorize the expected CA, a
+// missing or wrong record here can silently block certificate issuance for
+// the very domains this tool audits.
+func TestCAA(resolver Resolver, inputHost string, domain string, configParts []string) (string, bool, DNSSECStatus) {
 	var out strings.Builder
 	// 設定の書式
-	// txt @ v=spf1 array of values
-	// txt @ IP
-	// txt host(.) value
-	// txt host(.) v=spf1 value
-	// txt x._domainkey value
-	// txt _dmarc(subdomain) value
-	// txt value
+	// caa host flag tag value   (例 : caa @ 0 issue letsencrypt.org)
 
 	flag := false
-	var lookupvalue string
-	var confvalue string
-
-	// TXTレコードの様々なパターンを処理
-	if inputHost == "@" {
-		if strings.Contains(inputValue, "v=spf1") {
-			// case : txt @ v=spf1 value
-			lookupvalue = AdjustHostname(inputHost, domain)
-
-			// SPF値の場合は特別な処理：全ての値を結合して設定値とする
-			confvalue = inputValue // まず最初の値を設定
-			if 3 <= len(configParts) {
-				for i := 3; i < len(configParts); i++ {
-					confvalue += " " + configParts[i]
-				}
-			}
-		} else {
-			// case : txt @ IP
-			lookupvalue = AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
-			confvalue = inputValue
-		}
-	} else if strings.Contains(inputHost, domain) {
-		// case : txt host(.) value
-		lookupvalue = strings.TrimSuffix(inputHost, ".")
-		confvalue = inputValue // 最初の値を設定
-		if 3 <= len(configParts) {
-			for i := 3; i < len(configParts); i++ {
-				confvalue += " " + configParts[i]
-			}
-		}
-	} else if strings.Contains(inputValue, "v=spf1") {
-		// case : txt host(.) v=spf1 value
-		if strings.Contains(inputHost, domain) {
-			lookupvalue = strings.TrimSuffix(inputHost, ".")
-		} else {
-			lookupvalue = AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
-		}
-		confvalue = inputValue // 最初の値を設定
-		if 3 <= len(configParts) {
-			for i := 3; i < len(configParts); i++ {
-				confvalue += " " + configParts[i]
-			}
-		}
-	} else if strings.Contains(inputHost, "domainkey") {
-		// case : txt x._domainkey value
-		lookupvalue = AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
-		confvalue = inputValue // 最初の値を設定
-		if 3 <= len(configParts) {
-			for i := 3; i < len(configParts); i++ {
-				confvalue += " " + configParts[i]
-			}
-		}
-	} else if strings.Contains(inputHost, "dmarc") {
-		// case : txt _dmarc(subdomain) value
-		lookupvalue = "_dmarc." + domain
-		confvalue = inputValue // 最初の値を設定
-		if 3 <= len(configParts) {
-			for i := 3; i < len(configParts); i++ {
-				confvalue += " " + configParts[i]
-			}
-		}
-	} else {
-		// case : txt value
-		lookupvalue = AdjustHostname(inputHost, domain)
-		confvalue = inputValue // 最初の値を設定
-		if 3 <= len(configParts) {
-			for i := 3; i < len(configParts); i++ {
-				confvalue += " " + configParts[i]
-			}
-		}
+	lookupvalue := AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
+
+	var wantTag, wantValue string
+	switch len(configParts) {
+	case 5:
+		wantTag = configParts[3]
+		wantValue = configParts[4]
+	case 4:
+		wantTag = configParts[2]
+		wantValue = configParts[3]
 	}
 
 	// DNSルックアップを実行
-	txtrecords, err := net.LookupTXT(lookupvalue)
+	caarecords, status, err := resolver.LookupCAA(lookupvalue)
 	if err != nil {
 		out.WriteString(color.RedString("Error\n"))
-		out.WriteString(fmt.Sprintf("DNS lookup failed for TXT record: %v\n", err))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for CAA record: %v\n", err))
 		out.WriteString("\n")
-		return out.String(), false
+		return out.String(), false, status
 	}
 
 	// 結果を表示
-	out.WriteString("Type   : txt\n")
+	out.WriteString("Type   : caa\n")
 	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
 
-	// 一致するTXTレコードを探す
-	for _, txt := range txtrecords {
+	if len(caarecords) == 0 {
+		out.WriteString("missing CAA record - this can silently block certificate issuance\n")
+		out.WriteString(color.RedString("Error\n"))
+		writeDNSSECStatus(&out, status)
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
 
-		if txt == confvalue {
-			out.WriteString(fmt.Sprintf("Value  : %s\n", txt))
+	for _, caa := range caarecords {
+		out.WriteString(fmt.Sprintf("Value  : %d %s %q\n", caa.Flag, caa.Tag, caa.Value))
+		if caa.Tag == wantTag && caa.Value == wantValue {
 			flag = true
 		}
 	}
@@ -327,47 +1452,49 @@ func TestTXT(inputHost string, inputValue string, domain string, configParts []s
 	} else {
 		out.WriteString(color.RedString("Error\n"))
 	}
+	writeDNSSECStatus(&out, status)
 	out.WriteString("\n")
 
-	return out.String(), flag
+	return out.String(), flag, status
 }
 
-func TestNS(lookupvalue string, confvalue string, domain string) (string, bool) {
+// TestSRV checks that an SRV record's target host matches the config.
+// It only verifies the target - priority, weight and port are printed for
+// reference but not compared, so two records pointing at the same host with
+// different priority/weight/port both report OK.
+func TestSRV(resolver Resolver, lookupvalue string, confvalue string, domain string) (string, bool, DNSSECStatus) {
 	var out strings.Builder
 	// 設定の書式
-	// ns subdomain host
+	// svr name target(.)  (priority/weight/portは未検証、targetのみ比較)
 
 	flag := false
 
-	// 入力値を整形
+	// 入力値を整形(NS/CNAMEと同じ: すでにFQDNならそのまま、そうでなければdomainを付与)
 	if strings.Contains(lookupvalue, domain) {
-		// すでにドメインが含まれている場合はそのまま使用
 		lookupvalue = strings.TrimSuffix(lookupvalue, ".")
 	} else {
-		// ドメインが含まれていない場合は適切に調整
 		lookupvalue = AdjustHostname(strings.TrimSuffix(lookupvalue, "."), domain)
 	}
-
-	// 設定値から末尾のドットを削除
 	confvalue = strings.TrimSuffix(confvalue, ".")
 
 	// DNSルックアップを実行
-	nameserver, err := net.LookupNS(lookupvalue)
+	srvrecords, status, err := resolver.LookupSRV(lookupvalue)
 	if err != nil {
 		out.WriteString(color.RedString("Error\n"))
-		out.WriteString(fmt.Sprintf("DNS lookup failed for NS record: %v\n", err))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for SRV record: %v\n", err))
 		out.WriteString("\n")
-		return out.String(), false
+		return out.String(), false, status
 	}
 
 	// 結果を表示
-	out.WriteString("Type   : ns\n")
+	out.WriteString("Type   : svr\n")
 	out.WriteString(fmt.Sprintf("Name   : %s\n", lookupvalue))
 
-	// 一致するNSレコードを探す
-	for _, ns := range nameserver {
-		if strings.Contains(ns.Host, confvalue) {
-			out.WriteString(fmt.Sprintf("Value  : %s\n", ns.Host))
+	// 一致するSRVレコードを探す
+	for _, srv := range srvrecords {
+		target := strings.TrimSuffix(srv.Target, ".")
+		out.WriteString(fmt.Sprintf("Value  : %d %d %d %s\n", srv.Priority, srv.Weight, srv.Port, target))
+		if strings.Contains(target, confvalue) {
 			flag = true
 		}
 	}
@@ -377,9 +1504,99 @@ func TestNS(lookupvalue string, confvalue string, domain string) (string, bool)
 	} else {
 		out.WriteString(color.RedString("Error\n"))
 	}
+	writeDNSSECStatus(&out, status)
+	out.WriteString("\n")
+
+	return out.String(), flag, status
+}
+
+// TestALIAS resolves the ALIAS target's A/AAAA set and compares it against
+// what the apex itself resolves to, since an ALIAS is expected to be
+// transparently flattened to the same address set by the DNS provider.
+func TestALIAS(resolver Resolver, inputHost string, confvalue string, domain string) (string, bool, DNSSECStatus) {
+	var out strings.Builder
+
+	lookupvalue := AdjustHostname(strings.TrimSuffix(inputHost, "."), domain)
+	target := strings.TrimSuffix(confvalue, ".")
+
+	apexIPs, status, err := resolver.LookupA(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for ALIAS apex %s: %v\n", lookupvalue, err))
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
+	apexIP6s, _, err := resolver.LookupAAAA(lookupvalue)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for ALIAS apex %s (AAAA): %v\n", lookupvalue, err))
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
+	targetIPs, _, err := resolver.LookupA(target)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for ALIAS target %s: %v\n", target, err))
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
+	targetIP6s, _, err := resolver.LookupAAAA(target)
+	if err != nil {
+		out.WriteString(color.RedString("Error\n"))
+		out.WriteString(fmt.Sprintf("DNS lookup failed for ALIAS target %s (AAAA): %v\n", target, err))
+		out.WriteString("\n")
+		return out.String(), false, status
+	}
+
+	out.WriteString("Type   : alias\n")
+	out.WriteString(fmt.Sprintf("Name   : %s -> %s\n", lookupvalue, target))
+	out.WriteString(fmt.Sprintf("Value  : %s\n", joinIPs(append(append([]net.IP{}, apexIPs...), apexIP6s...))))
+
+	// The apex and target must agree on A and AAAA independently; a domain
+	// with no addresses of a given family on both sides is a match for that
+	// family (nothing to flatten), not a mismatch.
+	flag := sameIPSet(apexIPs, targetIPs) && sameIPSet(apexIP6s, targetIP6s)
+
+	if flag {
+		out.WriteString(color.GreenString("OK\n"))
+	} else {
+		out.WriteString(color.RedString("Error\n"))
+	}
+	writeDNSSECStatus(&out, status)
 	out.WriteString("\n")
 
-	return out.String(), flag
+	return out.String(), flag, status
+}
+
+func joinIPs(ips []net.IP) string {
+	strs := make([]string, len(ips))
+	for i, ip := range ips {
+		strs[i] = ip.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// sameIPSet reports whether every address the apex resolves to is also
+// among the target's addresses for the same address family. Two empty
+// sets match (neither side has that family); one empty and one non-empty
+// does not.
+func sameIPSet(apex []net.IP, target []net.IP) bool {
+	if len(apex) == 0 && len(target) == 0 {
+		return true
+	}
+	if len(apex) == 0 || len(target) == 0 {
+		return false
+	}
+	targetSet := make(map[string]bool, len(target))
+	for _, ip := range target {
+		targetSet[ip.String()] = true
+	}
+	for _, ip := range apex {
+		if !targetSet[ip.String()] {
+			return false
+		}
+	}
+	return true
 }
 
 func AdjustHostname(hostname string, domain string) string {
@@ -465,38 +1682,174 @@ func updateHistory(newDomain string) {
 	saveHistory(updated)
 }
 
+// bindDirectiveRegex detects a zone file's $ORIGIN/$TTL directives, and
+// bindRecordRegex detects a "name ... IN A/CNAME/..." resource record line -
+// either is enough to autodetect BIND zone syntax in loadConfigLines.
+var (
+	bindDirectiveRegex = regexp.MustCompile(`^\$(ORIGIN|TTL)\b`)
+	bindRecordRegex    = regexp.MustCompile(`(?i)\sIN\s+(A|AAAA|CNAME|MX|TXT|NS|SOA|CAA|SRV)\s`)
+)
+
+// loadConfigLines reads filename and returns one processLine-ready config
+// line per record, regardless of the file's on-disk format. Value-domain
+// files are read as-is; BIND zone files are parsed with miekg/dns and each
+// resource record is rendered back into the equivalent Value-domain syntax
+// so the rest of the pipeline (processLine, TestA/TestCNAME/...) doesn't
+// need to know which format the input came from.
+func loadConfigLines(filename string, domain string, format string) ([]string, error) {
+	useBind := format == "bind"
+	if format == "" || format == "auto" {
+		detected, err := detectBindFormat(filename)
+		if err != nil {
+			return nil, err
+		}
+		useBind = detected
+	}
+
+	if useBind {
+		return parseBindZone(filename, domain)
+	}
+	return readRawLines(filename)
+}
+
+// detectBindFormat peeks at the first few non-empty, non-comment lines of
+// filename looking for $ORIGIN/$TTL directives or an "IN <type>" token,
+// either of which only appear in BIND zone file syntax.
+func detectBindFormat(filename string) (bool, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return false, fmt.Errorf("Error when opening file")
+	}
+	defer fp.Close()
+
+	scanner := bufio.NewScanner(fp)
+	checked := 0
+	for scanner.Scan() && checked < 5 {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		checked++
+		if bindDirectiveRegex.MatchString(line) || bindRecordRegex.MatchString(" "+line+" ") {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("Error while reading file")
+	}
+	return false, nil
+}
+
+// readRawLines reads filename as a Value-domain config file, one line per
+// record, skipping blank lines exactly as performDNSCheck used to inline.
+func readRawLines(filename string) ([]string, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error when opening file")
+	}
+	defer fp.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error while reading file")
+	}
+	return lines, nil
+}
+
+// parseBindZone streams filename through miekg/dns's zone parser and
+// converts every supported resource record into the Value-domain config
+// line processLine already knows how to consume.
+func parseBindZone(filename string, domain string) ([]string, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error when opening file")
+	}
+	defer fp.Close()
+
+	zp := dns.NewZoneParser(fp, dns.Fqdn(domain), filename)
+
+	var lines []string
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		if line := bindRRToConfigLine(rr, domain); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("error parsing BIND zone file: %w", err)
+	}
+	return lines, nil
+}
+
+// bindRRToConfigLine renders a single parsed resource record back into
+// Value-domain syntax ("a host ip", "mx target priority host", ...).
+// Record types this tool doesn't otherwise check (SOA, etc.) are dropped.
+func bindRRToConfigLine(rr dns.RR, domain string) string {
+	host := strings.TrimSuffix(rr.Header().Name, ".")
+	bareDomain := strings.TrimSuffix(domain, ".")
+	switch {
+	case host == bareDomain:
+		host = "@"
+	case strings.HasSuffix(host, "."+bareDomain):
+		host = strings.TrimSuffix(host, "."+bareDomain)
+	}
+
+	switch v := rr.(type) {
+	case *dns.A:
+		return fmt.Sprintf("a %s %s", host, v.A.String())
+	case *dns.CNAME:
+		return fmt.Sprintf("cname %s %s", host, strings.TrimSuffix(v.Target, "."))
+	case *dns.MX:
+		return fmt.Sprintf("mx %s %d %s", strings.TrimSuffix(v.Mx, "."), v.Preference, host)
+	case *dns.TXT:
+		return fmt.Sprintf("txt %s %s", host, strings.Join(v.Txt, ""))
+	case *dns.NS:
+		return fmt.Sprintf("ns %s %s", host, strings.TrimSuffix(v.Ns, "."))
+	case *dns.AAAA:
+		return fmt.Sprintf("aaaa %s %s", host, v.AAAA.String())
+	case *dns.CAA:
+		return fmt.Sprintf("caa %s %d %s %s", host, v.Flag, v.Tag, v.Value)
+	case *dns.SRV:
+		return fmt.Sprintf("svr %s %s", host, strings.TrimSuffix(v.Target, "."))
+	default:
+		return ""
+	}
+}
+
 // DNSチェック処理を行う関数
-func performDNSCheck(domain string, filename string, parallel bool) {
+// format は "value"(Value-domain形式), "bind"(BINDゾーンファイル形式),
+// "auto"(ファイル内容から自動判定, デフォルト) のいずれか
+func performDNSCheck(domain string, filename string, parallel bool, resolvers []namedResolver, format string) {
 	// init default values
 	count_ok := 0
 	count_err := 0
 	count_untested := 0
+	count_bogus := 0
+	count_warning := 0
 
-	// Open file
-	fp, err := os.Open(filename)
+	lines, err := loadConfigLines(filename, domain, format)
 	if err != nil {
-		fmt.Println("Error when opening file")
+		fmt.Println(err)
 		return
 	}
-	defer fp.Close()
-
-	// Read file
-	scanner := bufio.NewScanner(fp)
 
 	if parallel {
 		var wg sync.WaitGroup
 		var mu sync.Mutex
 
-		for scanner.Scan() {
-			line := scanner.Text()
-			if len(line) == 0 {
-				continue
-			}
+		for _, line := range lines {
 			wg.Add(1)
 			go func(line string) {
 				defer wg.Done()
 
-				output, ok, untested := processLine(line, domain)
+				output, ok, untested, status, warnings := processLine(line, domain, resolvers)
 				if output == "" {
 					return
 				}
@@ -508,44 +1861,40 @@ func performDNSCheck(domain string, filename string, parallel bool) {
 				// Explicitly flush stdout to ensure immediate output
 				os.Stdout.Sync()
 
-				if untested > 0 {
+				if status == StatusBogus {
+					count_bogus++
+				} else if untested > 0 {
 					count_untested += untested
 				} else if ok {
 					count_ok++
 				} else {
 					count_err++
 				}
+				count_warning += warnings
 			}(line)
 		}
 		wg.Wait()
 	} else {
-		for scanner.Scan() {
-			line := scanner.Text()
-			if len(line) == 0 {
-				continue
-			}
-
-			output, ok, untested := processLine(line, domain)
+		for _, line := range lines {
+			output, ok, untested, status, warnings := processLine(line, domain, resolvers)
 			if output == "" {
 				continue
 			}
 			fmt.Print(output)
 
-			if untested > 0 {
+			if status == StatusBogus {
+				count_bogus++
+			} else if untested > 0 {
 				count_untested += untested
 			} else if ok {
 				count_ok++
 			} else {
 				count_err++
 			}
+			count_warning += warnings
 		}
 	}
 
-	if err = scanner.Err(); err != nil {
-		fmt.Println("Error while reading file")
-		return
-	}
-
 	fmt.Printf("-----------------\n")
 	fmt.Printf("Summary \n")
 	// OK
@@ -557,23 +1906,33 @@ func performDNSCheck(domain string, filename string, parallel bool) {
 	// Untested
 	yellow := color.New(color.FgYellow).PrintfFunc()
 	yellow("Untested : %s\n", strconv.Itoa(count_untested))
+	// Bogus (DNSSEC validation failure; only ever non-zero with --dnssec)
+	if count_bogus > 0 {
+		magenta := color.New(color.FgMagenta).PrintfFunc()
+		magenta("Bogus    : %s\n", strconv.Itoa(count_bogus))
+	}
+	// Warning (DMARC/DKIM records that need operator attention, whether or
+	// not they matched the config, e.g. p=none or a weak/revoked key)
+	if count_warning > 0 {
+		yellow("Warning  : %s\n", strconv.Itoa(count_warning))
+	}
 	fmt.Printf("-----------------\n")
 }
 
 // processLine processes a single line from the config file.
-func processLine(line string, domain string) (output string, ok bool, untested int) {
+func processLine(line string, domain string, resolvers []namedResolver) (output string, ok bool, untested int, status DNSSECStatus, warnings int) {
 	var out strings.Builder
 	splittedLine := strings.Fields(line)
 
 	// skip empty lines
 	if len(splittedLine) == 0 {
-		return "", false, 0
+		return "", false, 0, "", 0
 	}
 
 	// Skip comment
 	matched_comment := commentRegex.MatchString(splittedLine[0])
 	if matched_comment {
-		return "", false, 0 // empty output, not ok, no untested
+		return "", false, 0, "", 0 // empty output, not ok, no untested
 	}
 
 	// Print the config line
@@ -588,53 +1947,107 @@ func processLine(line string, domain string) (output string, ok bool, untested i
 	if len(splittedLine) > 1 && strings.Contains(splittedLine[1], "*") {
 		out.WriteString(color.YellowString("Untested : * (wildcard) used, test manually\n"))
 		out.WriteString("\n") // Empty line
-		return out.String(), false, 1
+		return out.String(), false, 1, "", 0
+	}
+
+	// Multi-resolver comparison mode (--authoritative / --compare-resolvers):
+	// run the same record through every resolver and render a matrix row
+	// instead of the usual single-resolver OK/Error block.
+	if len(resolvers) > 1 {
+		switch splittedLine[0] {
+		case "a", "cname", "mx", "txt", "ns":
+			dnshost = splittedLine[1]
+			confvalue := splittedLine[2]
+			row, rowOK := compareRecord(resolvers, splittedLine[0], dnshost, confvalue, domain, splittedLine)
+			out.WriteString(fmt.Sprintf("%s  %s\n", strings.Join(splittedLine, " "), row))
+			out.WriteString("\n")
+			return out.String(), rowOK, 0, "", 0
+		}
 	}
 
+	resolver := resolvers[0].Resolver
+
 	var testOutput string
 	var testOK bool
+	var testStatus DNSSECStatus
 
 	switch splittedLine[0] {
 	case "a":
 		dnshost = splittedLine[1]
 		confvalue := splittedLine[2]
-		testOutput, testOK = TestA(dnshost, confvalue, domain)
+		testOutput, testOK, testStatus = TestA(resolver, dnshost, confvalue, domain)
 		out.WriteString(testOutput)
-		return out.String(), testOK, 0
+		return out.String(), testOK, 0, testStatus, 0
 
 	case "cname":
 		dnshost = splittedLine[1]
 		confvalue := splittedLine[2]
-		testOutput, testOK = TestCNAME(dnshost, confvalue, domain)
+		testOutput, testOK, testStatus = TestCNAME(resolver, dnshost, confvalue, domain)
 		out.WriteString(testOutput)
-		return out.String(), testOK, 0
+		return out.String(), testOK, 0, testStatus, 0
 
 	case "mx":
-		testOutput, testOK = TestMX(splittedLine[1], splittedLine[2], domain, splittedLine)
+		testOutput, testOK, testStatus = TestMX(resolver, splittedLine[1], splittedLine[2], domain, splittedLine)
 		out.WriteString(testOutput)
-		return out.String(), testOK, 0
+		return out.String(), testOK, 0, testStatus, 0
 
 	case "txt":
-		testOutput, testOK = TestTXT(splittedLine[1], splittedLine[2], domain, splittedLine)
-		out.WriteString(testOutput)
-		return out.String(), testOK, 0
+		switch {
+		case strings.Contains(splittedLine[1], "domainkey"):
+			var testWarnings int
+			testOutput, testOK, testStatus, testWarnings = TestDKIM(resolver, splittedLine[1], splittedLine[2], domain, splittedLine)
+			out.WriteString(testOutput)
+			return out.String(), testOK, 0, testStatus, testWarnings
+		case strings.Contains(splittedLine[1], "dmarc"):
+			var testWarnings int
+			testOutput, testOK, testStatus, testWarnings = TestDMARC(resolver, splittedLine[1], splittedLine[2], domain, splittedLine)
+			out.WriteString(testOutput)
+			return out.String(), testOK, 0, testStatus, testWarnings
+		default:
+			var testUntested int
+			testOutput, testOK, testStatus, testUntested = TestTXT(resolver, splittedLine[1], splittedLine[2], domain, splittedLine)
+			out.WriteString(testOutput)
+			return out.String(), testOK, testUntested, testStatus, 0
+		}
 
 	case "ns":
 		dnshost = strings.TrimSuffix(splittedLine[1], ".")
 		confvalue := strings.TrimSuffix(splittedLine[2], ".")
-		testOutput, testOK = TestNS(dnshost, confvalue, domain)
+		testOutput, testOK, testStatus = TestNS(resolver, dnshost, confvalue, domain)
 		out.WriteString(testOutput)
-		return out.String(), testOK, 0
+		return out.String(), testOK, 0, testStatus, 0
 
-	case "svr", "caa", "alias", "aaaa":
-		out.WriteString(color.YellowString(fmt.Sprintf("Untested : %s record not yet implemented\n", splittedLine[0])))
-		out.WriteString("\n") // Empty line
-		return out.String(), false, 1
+	case "aaaa":
+		dnshost = splittedLine[1]
+		confvalue := splittedLine[2]
+		testOutput, testOK, testStatus = TestAAAA(resolver, dnshost, confvalue, domain)
+		out.WriteString(testOutput)
+		return out.String(), testOK, 0, testStatus, 0
+
+	case "caa":
+		dnshost = splittedLine[1]
+		testOutput, testOK, testStatus = TestCAA(resolver, dnshost, domain, splittedLine)
+		out.WriteString(testOutput)
+		return out.String(), testOK, 0, testStatus, 0
+
+	case "svr":
+		dnshost = splittedLine[1]
+		confvalue := splittedLine[2]
+		testOutput, testOK, testStatus = TestSRV(resolver, dnshost, confvalue, domain)
+		out.WriteString(testOutput)
+		return out.String(), testOK, 0, testStatus, 0
+
+	case "alias":
+		dnshost = splittedLine[1]
+		confvalue := splittedLine[2]
+		testOutput, testOK, testStatus = TestALIAS(resolver, dnshost, confvalue, domain)
+		out.WriteString(testOutput)
+		return out.String(), testOK, 0, testStatus, 0
 
 	default:
 		out.WriteString(color.YellowString("Untested : %s\n", splittedLine[0]))
 		out.WriteString("\n") // Empty line
-		return out.String(), false, 1
+		return out.String(), false, 1, "", 0
 	}
 }
 
@@ -783,11 +2196,43 @@ func main() {
 	var tmpFiles []string // 一時ファイルのリストを保持
 
 	parallel := false
+	var server string
+	var dnssec bool
+	var authoritative bool
+	var compareResolversSpec string
+	format := "auto"
 	processedArgs := []string{originalArgs[0]}
-	for _, arg := range originalArgs[1:] {
-		if arg == "-p" {
+	for i := 1; i < len(originalArgs); i++ {
+		arg := originalArgs[i]
+		switch {
+		case arg == "-p":
 			parallel = true
-		} else {
+		case arg == "--dnssec":
+			dnssec = true
+		case arg == "--authoritative":
+			authoritative = true
+		case arg == "--server":
+			if i+1 < len(originalArgs) {
+				server = originalArgs[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--server="):
+			server = strings.TrimPrefix(arg, "--server=")
+		case arg == "--compare-resolvers":
+			if i+1 < len(originalArgs) {
+				compareResolversSpec = originalArgs[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--compare-resolvers="):
+			compareResolversSpec = strings.TrimPrefix(arg, "--compare-resolvers=")
+		case arg == "--format":
+			if i+1 < len(originalArgs) {
+				format = originalArgs[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		default:
 			processedArgs = append(processedArgs, arg)
 		}
 	}
@@ -815,8 +2260,31 @@ func main() {
 			filename = args[2]
 		}
 
+		// 使用するリゾルバを決定する
+		// --authoritative / --compare-resolvers が指定されていれば複数リゾルバの
+		// 比較モード、それ以外は従来どおり単一リゾルバで実行する
+		var resolvers []namedResolver
+		switch {
+		case compareResolversSpec != "":
+			var err error
+			resolvers, err = compareResolvers(domain, compareResolversSpec, dnssec)
+			if err != nil {
+				fmt.Println(err)
+				resolvers = []namedResolver{{Name: "default", Resolver: newResolver(server, dnssec)}}
+			}
+		case authoritative:
+			var err error
+			resolvers, err = authoritativeResolvers(domain, dnssec)
+			if err != nil {
+				fmt.Println(err)
+				resolvers = []namedResolver{{Name: "default", Resolver: newResolver(server, dnssec)}}
+			}
+		default:
+			resolvers = []namedResolver{{Name: "default", Resolver: newResolver(server, dnssec)}}
+		}
+
 		// DNSチェックを実行
-		performDNSCheck(domain, filename, parallel)
+		performDNSCheck(domain, filename, parallel, resolvers, format)
 
 		// Only ask for rerun if in interactive mode
 		if isInteractive {